@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// SetDefaults implements apis.Defaultable, defaulting SchedulerPolicy
+// to RandomSchedulerPolicy when left empty
+func (cluster *Cluster) SetDefaults(ctx context.Context) {
+	if cluster.Spec.SchedulerPolicy == "" {
+		cluster.Spec.SchedulerPolicy = RandomSchedulerPolicy
+	}
+}
+
+// Validate implements apis.Validatable
+func (cluster *Cluster) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	switch cluster.Spec.SchedulerPolicy {
+	case "", RandomSchedulerPolicy, BinPackSchedulerPolicy, SpreadSchedulerPolicy:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(cluster.Spec.SchedulerPolicy, "spec.schedulerPolicy"))
+	}
+	return errs
+}
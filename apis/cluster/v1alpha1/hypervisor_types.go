@@ -0,0 +1,57 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HypervisorSpec defines the desired state of a Hypervisor
+type HypervisorSpec struct {
+	// Public marks this hypervisor as reachable from outside the
+	// cluster, making it eligible for worker node scheduling. Hypervisors
+	// left private are only eligible for control plane nodes
+	// +optional
+	Public bool `json:"public,omitempty"`
+}
+
+// HypervisorStatus defines the observed state of a Hypervisor
+type HypervisorStatus struct{}
+
+// +kubebuilder:object:root=true
+
+// Hypervisor is the Schema for the hypervisors API
+type Hypervisor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HypervisorSpec   `json:"spec,omitempty"`
+	Status HypervisorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HypervisorList contains a list of Hypervisor
+type HypervisorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Hypervisor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Hypervisor{}, &HypervisorList{})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+
+	v1beta1 "oneinfra.ereslibre.es/m/apis/cluster/v1beta1"
+)
+
+// ConvertTo implements apis.Convertible, converting this v1alpha1 Node
+// into the given v1beta1 sink
+func (node *Node) ConvertTo(ctx context.Context, sink apis.Convertible) error {
+	beta, ok := sink.(*v1beta1.Node)
+	if !ok {
+		return convertibleTypeError(sink)
+	}
+	beta.ObjectMeta = node.ObjectMeta
+	beta.Spec = v1beta1.NodeSpec{
+		Hypervisor: node.Spec.Hypervisor,
+		Cluster:    node.Spec.Cluster,
+		Role:       v1beta1.Role(node.Spec.Role),
+	}
+	return nil
+}
+
+// ConvertFrom implements apis.Convertible, converting a v1beta1 Node
+// source into this v1alpha1 Node
+func (node *Node) ConvertFrom(ctx context.Context, source apis.Convertible) error {
+	beta, ok := source.(*v1beta1.Node)
+	if !ok {
+		return convertibleTypeError(source)
+	}
+	node.ObjectMeta = beta.ObjectMeta
+	node.Spec = NodeSpec{
+		Hypervisor: beta.Spec.Hypervisor,
+		Cluster:    beta.Spec.Cluster,
+		Role:       Role(beta.Spec.Role),
+	}
+	return nil
+}
+
+func convertibleTypeError(obj apis.Convertible) error {
+	return apis.ErrGeneric("unsupported conversion type", "").ViaField(
+		"/apis/cluster/v1alpha1",
+	)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeConditionType is a readable condition tracked for a Node
+type NodeConditionType string
+
+const (
+	// NodeReady indicates the node's components have been reconciled
+	// successfully and are healthy
+	NodeReady NodeConditionType = "Ready"
+	// NodeProgressing indicates the node controller is actively
+	// working towards reconciling the node
+	NodeProgressing NodeConditionType = "Progressing"
+	// NodeDegraded indicates the node failed to reconcile and is
+	// being retried
+	NodeDegraded NodeConditionType = "Degraded"
+)
+
+// NodeCondition is a status condition for a Node
+type NodeCondition struct {
+	Type               NodeConditionType      `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+}
+
+// NodeStatus reflects the observed state of a Node, maintained by
+// NodeController as it reconciles the node's components
+type NodeStatus struct {
+	// Conditions holds the latest observed conditions for this node
+	// +optional
+	Conditions []NodeCondition `json:"conditions,omitempty"`
+	// ObservedGeneration is the most recent spec generation the
+	// controller has reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
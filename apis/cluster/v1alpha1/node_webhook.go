@@ -0,0 +1,62 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// SetDefaults implements apis.Defaultable, defaulting Role to
+// ControlPlaneRole and picking a hypervisor through the configured
+// scheduler when Spec.Hypervisor is left empty, preserving today's
+// Export() behavior for nodes that don't set a role
+func (node *Node) SetDefaults(ctx context.Context) {
+	if node.Spec.Role == "" {
+		node.Spec.Role = ControlPlaneRole
+	}
+	if node.Spec.Hypervisor == "" {
+		if hypervisor, ok := defaultHypervisorFromContext(ctx, node); ok {
+			node.Spec.Hypervisor = hypervisor
+		}
+	}
+}
+
+// Validate implements apis.Validatable
+func (node *Node) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if node.Spec.Cluster == "" {
+		errs = errs.Also(apis.ErrMissingField("spec.cluster"))
+	}
+	if node.Spec.Role != ControlPlaneRole && node.Spec.Role != WorkerRole {
+		errs = errs.Also(apis.ErrInvalidValue(node.Spec.Role, "spec.role"))
+	}
+	if node.Spec.Cluster != "" && !clusterExists(ctx, node.Spec.Cluster) {
+		errs = errs.Also(apis.ErrInvalidValue(node.Spec.Cluster, "spec.cluster"))
+	}
+	if node.Spec.Hypervisor != "" && !hypervisorExists(ctx, node.Spec.Hypervisor) {
+		errs = errs.Also(apis.ErrInvalidValue(node.Spec.Hypervisor, "spec.hypervisor"))
+	}
+	if !apis.IsInUpdate(ctx) && nodeNameCollides(ctx, node.Spec.Cluster, node.Name) {
+		errs = errs.Also(&apis.FieldError{
+			Message: "node name already exists in this cluster",
+			Paths:   []string{"metadata.name"},
+		})
+	}
+	return errs
+}
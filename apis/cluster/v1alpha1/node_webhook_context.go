@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "context"
+
+// Store gives the Node defaulting/validating webhooks read access to
+// the current set of clusters, hypervisors and nodes, without
+// requiring this package to depend on a concrete client implementation
+type Store interface {
+	ClusterExists(name string) bool
+	HypervisorExists(name string) bool
+	NodeExists(clusterName, nodeName string) bool
+	PickHypervisor(node *Node) (string, bool)
+}
+
+type storeKey struct{}
+
+// WithStore returns a context carrying store, read by Node's
+// SetDefaults and Validate. Without a store in the context, the cluster
+// and hypervisor existence checks pass, name collisions are not
+// detected and no hypervisor is defaulted; cmd/webhook does not install
+// a store
+func WithStore(ctx context.Context, store Store) context.Context {
+	return context.WithValue(ctx, storeKey{}, store)
+}
+
+func storeFromContext(ctx context.Context) (Store, bool) {
+	store, ok := ctx.Value(storeKey{}).(Store)
+	return store, ok
+}
+
+func clusterExists(ctx context.Context, name string) bool {
+	store, ok := storeFromContext(ctx)
+	return !ok || store.ClusterExists(name)
+}
+
+func hypervisorExists(ctx context.Context, name string) bool {
+	store, ok := storeFromContext(ctx)
+	return !ok || store.HypervisorExists(name)
+}
+
+func nodeNameCollides(ctx context.Context, clusterName, nodeName string) bool {
+	store, ok := storeFromContext(ctx)
+	return ok && store.NodeExists(clusterName, nodeName)
+}
+
+func defaultHypervisorFromContext(ctx context.Context, node *Node) (string, bool) {
+	store, ok := storeFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return store.PickHypervisor(node)
+}
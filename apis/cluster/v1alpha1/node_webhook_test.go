@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+type fakeStore struct {
+	existingNode bool
+}
+
+func (s *fakeStore) ClusterExists(name string) bool    { return true }
+func (s *fakeStore) HypervisorExists(name string) bool { return true }
+func (s *fakeStore) NodeExists(clusterName, nodeName string) bool {
+	return s.existingNode
+}
+func (s *fakeStore) PickHypervisor(node *Node) (string, bool) { return "", false }
+
+func validNode() *Node {
+	return &Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Spec: NodeSpec{
+			Cluster: "cluster1",
+			Role:    ControlPlaneRole,
+		},
+	}
+}
+
+func TestNodeValidateRejectsNameCollisionOnCreate(t *testing.T) {
+	ctx := WithStore(context.Background(), &fakeStore{existingNode: true})
+
+	if errs := validNode().Validate(ctx); errs == nil {
+		t.Fatal("expected a validation error for a colliding node name on create, got nil")
+	}
+}
+
+func TestNodeValidateAllowsExistingNameOnUpdate(t *testing.T) {
+	ctx := WithStore(context.Background(), &fakeStore{existingNode: true})
+	ctx = apis.WithinUpdate(ctx, validNode())
+
+	if errs := validNode().Validate(ctx); errs != nil {
+		t.Fatalf("expected no validation error for an update to an existing node, got: %s", errs)
+	}
+}
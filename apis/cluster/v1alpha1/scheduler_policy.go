@@ -0,0 +1,34 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// SchedulerPolicy identifies the hypervisor scheduler used to place
+// the nodes of a cluster. It is surfaced on ClusterSpec.SchedulerPolicy
+// so operators can pick a placement strategy per cluster.
+type SchedulerPolicy string
+
+const (
+	// RandomSchedulerPolicy places nodes on a random hypervisor out of
+	// the eligible candidates. This is the default.
+	RandomSchedulerPolicy SchedulerPolicy = "Random"
+	// BinPackSchedulerPolicy prefers hypervisors that are already
+	// hosting components, minimizing fragmentation across the pool.
+	BinPackSchedulerPolicy SchedulerPolicy = "BinPack"
+	// SpreadSchedulerPolicy prefers hypervisors hosting the fewest
+	// nodes of the same cluster, spreading a cluster across the pool.
+	SpreadSchedulerPolicy SchedulerPolicy = "Spread"
+)
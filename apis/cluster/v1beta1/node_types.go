@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 is the next Node API version. It is storage-less
+// today: every Node is stored as v1alpha1. v1alpha1.Node converts to
+// and from this version through its ConvertTo and ConvertFrom methods;
+// the conversion webhook in cmd/webhook registers only the v1alpha1
+// version of Node, so it does not serve conversions to or from v1beta1.
+// Its shape is currently identical to v1alpha1, giving room for the
+// schema to diverge later without breaking existing v1alpha1 manifests.
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Role represents the role of a node within a cluster
+type Role string
+
+const (
+	// ControlPlaneRole identifies a control plane node
+	ControlPlaneRole Role = "ControlPlane"
+	// WorkerRole identifies a worker node
+	WorkerRole Role = "Worker"
+)
+
+// NodeSpec defines the desired state of a Node
+type NodeSpec struct {
+	Hypervisor string `json:"hypervisor"`
+	Cluster    string `json:"cluster"`
+	Role       Role   `json:"role"`
+}
+
+// +kubebuilder:object:root=true
+
+// Node represents a cluster node
+type Node struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NodeSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeList contains a list of Node
+type NodeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Node `json:"items"`
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1alpha1 "oneinfra.ereslibre.es/m/apis/cluster/v1alpha1"
+)
+
+// OneInfraMachineSpec defines the desired state of a OneInfraMachine
+type OneInfraMachineSpec struct {
+	// Cluster is the name of the oneinfra cluster this machine joins
+	Cluster string `json:"cluster"`
+	// Role is the role of the oneinfra node backing this machine
+	// (control-plane or worker)
+	Role clusterv1alpha1.Role `json:"role"`
+	// Hypervisor optionally pins this machine to a specific
+	// hypervisor; when empty, one is picked by the cluster's
+	// scheduler policy
+	// +optional
+	Hypervisor string `json:"hypervisor,omitempty"`
+	// ProviderID is set by this provider once the backing oneinfra
+	// node has been reconciled, and copied onto the owning CAPI Machine
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+}
+
+// OneInfraMachineStatus defines the observed state of a OneInfraMachine
+type OneInfraMachineStatus struct {
+	// Ready denotes that the backing oneinfra node has been reconciled
+	Ready bool `json:"ready,omitempty"`
+	// Addresses are the addresses reported for the backing oneinfra node
+	// +optional
+	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
+	// FailureReason, when set, holds the error of the most recent failed
+	// attempt to reconcile the backing oneinfra node; the attempt is
+	// retried
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// OneInfraMachine is the Schema for the oneinframachines API. It is
+// the oneinfra Cluster API infrastructure provider's representation
+// of a CAPI Machine, and is reconciled into a clusterv1alpha1.Node
+type OneInfraMachine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OneInfraMachineSpec   `json:"spec,omitempty"`
+	Status OneInfraMachineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OneInfraMachineList contains a list of OneInfraMachine
+type OneInfraMachineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OneInfraMachine `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OneInfraMachine{}, &OneInfraMachineList{})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OneInfraMachineTemplateResource describes the data needed to create
+// a OneInfraMachine from a template
+type OneInfraMachineTemplateResource struct {
+	Spec OneInfraMachineSpec `json:"spec"`
+}
+
+// OneInfraMachineTemplateSpec defines the desired state of a OneInfraMachineTemplate
+type OneInfraMachineTemplateSpec struct {
+	Template OneInfraMachineTemplateResource `json:"template"`
+}
+
+// +kubebuilder:object:root=true
+
+// OneInfraMachineTemplate is the Schema for the
+// oneinframachinetemplates API, used by a MachineSet/MachineDeployment
+// to stamp out OneInfraMachines
+type OneInfraMachineTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OneInfraMachineTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OneInfraMachineTemplateList contains a list of OneInfraMachineTemplate
+type OneInfraMachineTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OneInfraMachineTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OneInfraMachineTemplate{}, &OneInfraMachineTemplateList{})
+}
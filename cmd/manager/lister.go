@@ -0,0 +1,57 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	clusterv1alpha1 "oneinfra.ereslibre.es/m/apis/cluster/v1alpha1"
+	clientset "oneinfra.ereslibre.es/m/internal/pkg/generated/clientset/versioned"
+	informers "oneinfra.ereslibre.es/m/internal/pkg/generated/informers/externalversions"
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+// clientsetLister implements node.NodeLister on top of the generated
+// oneinfra clientset and its shared informer caches
+type clientsetLister struct {
+	clientset       clientset.Interface
+	informerFactory informers.SharedInformerFactory
+}
+
+func newClientsetLister(clientset clientset.Interface, informerFactory informers.SharedInformerFactory) *clientsetLister {
+	return &clientsetLister{clientset: clientset, informerFactory: informerFactory}
+}
+
+func (l *clientsetLister) GetNode(clusterName, nodeName string) (*clusterv1alpha1.Node, error) {
+	return l.informerFactory.Cluster().V1alpha1().Nodes().Lister().Nodes(clusterName).Get(nodeName)
+}
+
+func (l *clientsetLister) ListNodes(clusterName string) ([]*clusterv1alpha1.Node, error) {
+	return l.informerFactory.Cluster().V1alpha1().Nodes().Lister().Nodes(clusterName).List(labels.Everything())
+}
+
+func (l *clientsetLister) GetHypervisor(hypervisorName string) (*infra.Hypervisor, error) {
+	return l.informerFactory.Infra().V1alpha1().Hypervisors().Lister().Get(hypervisorName)
+}
+
+func (l *clientsetLister) UpdateNodeStatus(node *clusterv1alpha1.Node) error {
+	_, err := l.clientset.ClusterV1alpha1().Nodes(node.Namespace).UpdateStatus(context.Background(), node, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command manager runs the long-running oneinfra node controller. It
+// replaces invoking Node.Reconcile() once from the CLI with a
+// workqueue-driven control loop that keeps retrying until nodes
+// converge, optionally across multiple leader-elected manager replicas.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	clientset "oneinfra.ereslibre.es/m/internal/pkg/generated/clientset/versioned"
+	informers "oneinfra.ereslibre.es/m/internal/pkg/generated/informers/externalversions"
+	"oneinfra.ereslibre.es/m/internal/pkg/node"
+)
+
+func main() {
+	var kubeconfig string
+	var threadiness int
+	var leaderElect bool
+	flag.StringVar(&kubeconfig, "kubeconfig", "", "path to a kubeconfig, only required if out-of-cluster")
+	flag.IntVar(&threadiness, "threadiness", 2, "number of node workers to run")
+	flag.BoolVar(&leaderElect, "leader-elect", false, "run with leader election, allowing multiple manager replicas")
+	flag.Parse()
+
+	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		klog.Fatalf("could not build kubeconfig: %s", err)
+	}
+
+	kubeclientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("could not build kubernetes clientset: %s", err)
+	}
+	oneinfraclientset, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("could not build oneinfra clientset: %s", err)
+	}
+
+	run := func(ctx context.Context) {
+		stopCh := ctx.Done()
+
+		informerFactory := informers.NewSharedInformerFactory(oneinfraclientset, 30*time.Second)
+		lister := newClientsetLister(oneinfraclientset, informerFactory)
+		controller := node.NewNodeController(
+			kubeclientset,
+			lister,
+			informerFactory.Cluster().V1alpha1().Nodes().Informer(),
+			informerFactory.Cluster().V1alpha1().Clusters().Informer(),
+			informerFactory.Infra().V1alpha1().Hypervisors().Informer(),
+			record.NewBroadcaster(),
+		)
+
+		informerFactory.Start(stopCh)
+
+		if err := controller.Run(threadiness, stopCh); err != nil {
+			klog.Fatalf("error running node controller: %s", err)
+		}
+	}
+
+	if !leaderElect {
+		run(context.Background())
+		return
+	}
+
+	id, err := os.Hostname()
+	if err != nil {
+		id = uuid.New().String()
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      "oneinfra-node-controller",
+			Namespace: "oneinfra-system",
+		},
+		Client: kubeclientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: id,
+		},
+	}
+
+	leaderelection.RunOrDie(context.Background(), leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: run,
+			OnStoppedLeading: func() {
+				klog.Infof("%s: leadership lost, exiting", id)
+				os.Exit(0)
+			},
+		},
+	})
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command webhook registers the defaulting, validating and conversion
+// webhooks for the oneinfra cluster CRDs (Node, Cluster, Hypervisor),
+// following the knative.dev/pkg webhook pattern.
+package main
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/signals"
+	"knative.dev/pkg/webhook/certificates"
+	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/pkg/webhook/resourcesemantics/conversion"
+	"knative.dev/pkg/webhook/resourcesemantics/defaulting"
+	"knative.dev/pkg/webhook/resourcesemantics/validation"
+
+	clusterv1alpha1 "oneinfra.ereslibre.es/m/apis/cluster/v1alpha1"
+)
+
+const (
+	webhookName           = "webhook.oneinfra.ereslibre.es"
+	defaultingWebhookName = "defaulting." + webhookName
+	validationWebhookName = "validating." + webhookName
+	conversionWebhookName = "conversion." + webhookName
+	certReconcilerName    = "oneinfra-webhook-certs"
+)
+
+func newDefaultingAdmissionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	return defaulting.NewAdmissionController(ctx,
+		defaultingWebhookName,
+		"/defaulting",
+		resourceTypes(),
+		func(ctx context.Context) context.Context { return ctx },
+		true,
+	)
+}
+
+func newValidationAdmissionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	return validation.NewAdmissionController(ctx,
+		validationWebhookName,
+		"/validating",
+		resourceTypes(),
+		func(ctx context.Context) context.Context { return ctx },
+		true,
+	)
+}
+
+func newConversionController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	return conversion.NewConversionController(ctx,
+		"/conversion",
+		map[schema.GroupKind]conversion.GroupKindConversion{
+			clusterv1alpha1.GroupVersion.WithKind("Node").GroupKind(): {
+				DefinitionName: "nodes.cluster.oneinfra.ereslibre.es",
+				HubVersion:     clusterv1alpha1.GroupVersion.Version,
+				Zygotes: map[string]conversion.ConvertibleObject{
+					clusterv1alpha1.GroupVersion.Version: &clusterv1alpha1.Node{},
+				},
+			},
+		},
+		func(ctx context.Context) (context.Context, error) { return ctx, nil },
+	)
+}
+
+func resourceTypes() map[schema.GroupVersionKind]resourcesemantics.GenericCRD {
+	return map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
+		clusterv1alpha1.GroupVersion.WithKind("Node"):       &clusterv1alpha1.Node{},
+		clusterv1alpha1.GroupVersion.WithKind("Cluster"):    &clusterv1alpha1.Cluster{},
+		clusterv1alpha1.GroupVersion.WithKind("Hypervisor"): &clusterv1alpha1.Hypervisor{},
+	}
+}
+
+func main() {
+	ctx := signals.NewContext()
+	sharedmain.MainWithContext(ctx, webhookName,
+		certificates.NewController,
+		newDefaultingAdmissionController,
+		newValidationAdmissionController,
+		newConversionController,
+	)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oneinframachine implements the Cluster API infrastructure
+// provider controller: it watches CAPI Machine objects referencing a
+// OneInfraMachine and materializes them as oneinfra Nodes.
+package oneinframachine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1alpha3 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	clusterv1alpha1 "oneinfra.ereslibre.es/m/apis/cluster/v1alpha1"
+	infrav1alpha1 "oneinfra.ereslibre.es/m/apis/infrastructure/v1alpha1"
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+	"oneinfra.ereslibre.es/m/internal/pkg/node"
+)
+
+// oneInfraMachineFinalizer is added to every OneInfraMachine this
+// controller materializes a node for, so its components are torn down
+// before the object is allowed to be garbage collected
+const oneInfraMachineFinalizer = "oneinfra.ereslibre.es/machine"
+
+// Reconciler reconciles a OneInfraMachine object by materializing the
+// oneinfra Node it describes and reporting its status back onto both
+// the OneInfraMachine and its owning CAPI Machine
+type Reconciler struct {
+	client.Client
+
+	// HypervisorList is the pool of hypervisors available for
+	// scheduling newly created nodes
+	HypervisorList infra.HypervisorList
+}
+
+// Reconcile implements the main reconciliation loop for OneInfraMachine objects
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	oneInfraMachine := &infrav1alpha1.OneInfraMachine{}
+	if err := r.Get(ctx, req.NamespacedName, oneInfraMachine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err, "could not fetch OneInfraMachine")
+	}
+
+	if !oneInfraMachine.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDeleteRequest(ctx, oneInfraMachine)
+	}
+
+	if !controllerutil.ContainsFinalizer(oneInfraMachine, oneInfraMachineFinalizer) {
+		controllerutil.AddFinalizer(oneInfraMachine, oneInfraMachineFinalizer)
+		if err := r.Update(ctx, oneInfraMachine); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "could not add finalizer to OneInfraMachine")
+		}
+	}
+
+	machine, err := util.GetOwnerMachine(ctx, r.Client, oneInfraMachine.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "could not fetch owner Machine")
+	}
+	if machine == nil {
+		// Owner Machine has not been set yet by the CAPI core controller
+		return ctrl.Result{}, nil
+	}
+
+	oneInfraNode, err := r.reconcileNode(ctx, oneInfraMachine)
+	if err != nil {
+		oneInfraMachine.Status.FailureReason = err.Error()
+		if updateErr := r.Status().Update(ctx, oneInfraMachine); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	oneInfraMachine.Spec.Hypervisor = oneInfraNode.HypervisorName
+	oneInfraMachine.Spec.ProviderID = providerIDForNode(oneInfraNode)
+	if err := r.Update(ctx, oneInfraMachine); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "could not update OneInfraMachine spec")
+	}
+	// Update refreshes oneInfraMachine from the server response, so the
+	// status is only filled in after it
+	oneInfraMachine.Status.Ready = true
+	oneInfraMachine.Status.Addresses = []corev1.NodeAddress{
+		{Type: corev1.NodeInternalDNS, Address: fmt.Sprintf("%s.%s", oneInfraNode.Name, oneInfraNode.ClusterName)},
+	}
+	if err := r.Status().Update(ctx, oneInfraMachine); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "could not update OneInfraMachine status")
+	}
+
+	machine.Spec.ProviderID = &oneInfraMachine.Spec.ProviderID
+	if err := r.Update(ctx, machine); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "could not update Machine providerID")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileNode materializes and reconciles the oneinfra node backing oneInfraMachine
+func (r *Reconciler) reconcileNode(ctx context.Context, oneInfraMachine *infrav1alpha1.OneInfraMachine) (*node.Node, error) {
+	schedulerPolicy, err := r.clusterSchedulerPolicy(ctx, oneInfraMachine.Spec.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var oneInfraNode *node.Node
+	if oneInfraMachine.Spec.Hypervisor != "" {
+		hypervisor := r.HypervisorList.WithName(oneInfraMachine.Spec.Hypervisor)
+		if hypervisor == nil {
+			return nil, errors.Errorf("hypervisor %q does not exist", oneInfraMachine.Spec.Hypervisor)
+		}
+		oneInfraNode = node.NewNodeWithScheduler(
+			oneInfraMachine.Name,
+			oneInfraMachine.Spec.Cluster,
+			oneInfraMachine.Spec.Role,
+			node.SchedulerFor(schedulerPolicy),
+			infra.HypervisorList{hypervisor},
+			nil,
+		)
+	} else {
+		oneInfraNode = node.NewNodeWithRandomHypervisor(
+			oneInfraMachine.Name,
+			oneInfraMachine.Spec.Cluster,
+			oneInfraMachine.Spec.Role,
+			schedulerPolicy,
+			r.HypervisorList,
+		)
+	}
+	if oneInfraMachine.Spec.Role == clusterv1alpha1.WorkerRole {
+		apiServerHypervisor, err := r.clusterAPIServerHypervisor(ctx, oneInfraMachine.Spec.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		oneInfraNode.SetAPIServerHypervisor(apiServerHypervisor)
+	}
+	if err := oneInfraNode.Reconcile(); err != nil {
+		return nil, errors.Wrap(err, "could not reconcile oneinfra node")
+	}
+	return oneInfraNode, nil
+}
+
+// clusterSchedulerPolicy returns the scheduler policy configured on
+// clusterName's Cluster object, defaulting to the empty policy (which
+// SchedulerFor resolves to RandomSchedulerPolicy) when the Cluster
+// cannot be found yet
+func (r *Reconciler) clusterSchedulerPolicy(ctx context.Context, clusterName string) (clusterv1alpha1.SchedulerPolicy, error) {
+	cluster := &clusterv1alpha1.Cluster{}
+	if err := r.Get(ctx, client.ObjectKey{Name: clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "could not fetch cluster %q", clusterName)
+	}
+	return cluster.Spec.SchedulerPolicy, nil
+}
+
+// clusterAPIServerHypervisor returns the hypervisor hosting
+// clusterName's kube-apiserver, resolved from the control plane
+// OneInfraMachines already scheduled for that cluster
+func (r *Reconciler) clusterAPIServerHypervisor(ctx context.Context, clusterName string) (*infra.Hypervisor, error) {
+	var siblings infrav1alpha1.OneInfraMachineList
+	if err := r.List(ctx, &siblings); err != nil {
+		return nil, errors.Wrapf(err, "could not list machines for cluster %q", clusterName)
+	}
+	for _, sibling := range siblings.Items {
+		if sibling.Spec.Cluster != clusterName || sibling.Spec.Role == clusterv1alpha1.WorkerRole || sibling.Spec.Hypervisor == "" {
+			continue
+		}
+		if hypervisor := r.HypervisorList.WithName(sibling.Spec.Hypervisor); hypervisor != nil {
+			return hypervisor, nil
+		}
+	}
+	return nil, errors.Errorf("no control plane hypervisor found yet for cluster %q", clusterName)
+}
+
+// reconcileDeleteRequest handles an OneInfraMachine marked for
+// deletion: it tears down the components its node had reconciled, then
+// releases the finalizer so the object can be garbage collected
+func (r *Reconciler) reconcileDeleteRequest(ctx context.Context, oneInfraMachine *infrav1alpha1.OneInfraMachine) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(oneInfraMachine, oneInfraMachineFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.reconcileDelete(oneInfraMachine); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "could not tear down oneinfra node")
+	}
+
+	controllerutil.RemoveFinalizer(oneInfraMachine, oneInfraMachineFinalizer)
+	if err := r.Update(ctx, oneInfraMachine); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "could not remove finalizer from OneInfraMachine")
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileDelete tears down the components this OneInfraMachine's
+// node had reconciled onto its hypervisor, as required by the CAPI
+// infrastructure provider contract
+func (r *Reconciler) reconcileDelete(oneInfraMachine *infrav1alpha1.OneInfraMachine) error {
+	hypervisor := r.HypervisorList.WithName(oneInfraMachine.Spec.Hypervisor)
+	if hypervisor == nil {
+		return nil
+	}
+	versionedNode := &clusterv1alpha1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: oneInfraMachine.Name},
+		Spec: clusterv1alpha1.NodeSpec{
+			Hypervisor: oneInfraMachine.Spec.Hypervisor,
+			Cluster:    oneInfraMachine.Spec.Cluster,
+			Role:       oneInfraMachine.Spec.Role,
+		},
+	}
+	oneInfraNode, err := node.NewNodeWithHypervisorFromv1alpha1(versionedNode, hypervisor)
+	if err != nil {
+		return errors.Wrap(err, "could not materialize oneinfra node")
+	}
+	return oneInfraNode.Delete()
+}
+
+func providerIDForNode(n *node.Node) string {
+	return fmt.Sprintf("oneinfra://%s/%s", n.ClusterName, n.Name)
+}
+
+// SetupWithManager wires the Reconciler with the controller manager,
+// watching OneInfraMachine objects and their owning CAPI Machines
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1alpha1.OneInfraMachine{}).
+		Watches(
+			&clusterapiv1alpha3.Machine{},
+			util.EnqueueRequestsForMachine(mgr.GetClient(), infrav1alpha1.GroupVersion.WithKind("OneInfraMachine")),
+		).
+		Complete(r)
+}
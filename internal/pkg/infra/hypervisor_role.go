@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package infra
+
+// Public returns the subset of this hypervisor list that is tagged
+// public, suitable for scheduling worker nodes that need to be
+// reachable from outside the cluster
+func (hypervisorList HypervisorList) Public() HypervisorList {
+	return hypervisorList.withPublicTag(true)
+}
+
+// Private returns the subset of this hypervisor list that is not
+// tagged public, suitable for scheduling control plane nodes
+func (hypervisorList HypervisorList) Private() HypervisorList {
+	return hypervisorList.withPublicTag(false)
+}
+
+func (hypervisorList HypervisorList) withPublicTag(public bool) HypervisorList {
+	var res HypervisorList
+	for _, hypervisor := range hypervisorList {
+		if hypervisor.Public == public {
+			res = append(res, hypervisor)
+		}
+	}
+	return res
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+// bootstrapKubeConfigFor generates a kubeconfig for clusterName,
+// pointing at that cluster's kube-apiserver and authenticated with a
+// newly generated bootstrap token, suitable for worker components
+// (kubelet, kube-proxy) joining the cluster. apiServerHypervisor must
+// be the hypervisor actually hosting that cluster's
+// KubeAPIServerComponent, which for a worker node is never its own
+// hypervisor (workers and control plane nodes are scheduled onto
+// disjoint public/private hypervisor pools)
+func bootstrapKubeConfigFor(clusterName string, apiServerHypervisor *infra.Hypervisor) (string, error) {
+	if apiServerHypervisor == nil {
+		return "", errors.Errorf("could not resolve the kube-apiserver hypervisor for cluster %q", clusterName)
+	}
+	apiServerEndpoint, err := apiServerHypervisor.ComponentEndpoint(clusterName, "kube-apiserver")
+	if err != nil {
+		return "", errors.Wrapf(err, "could not resolve kube-apiserver endpoint for cluster %q", clusterName)
+	}
+	bootstrapToken, err := newBootstrapToken()
+	if err != nil {
+		return "", errors.Wrap(err, "could not generate bootstrap token")
+	}
+	return fmt.Sprintf(bootstrapKubeConfigTemplate, apiServerEndpoint, bootstrapToken), nil
+}
+
+func kubeletBootstrapKubeConfigPath(clusterName string) string {
+	return fmt.Sprintf("/etc/kubernetes/%s-kubelet-bootstrap.conf", clusterName)
+}
+
+func kubeProxyKubeConfigPath(clusterName string) string {
+	return fmt.Sprintf("/etc/kubernetes/%s-kube-proxy.conf", clusterName)
+}
+
+const bootstrapKubeConfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://%s
+    insecure-skip-tls-verify: true
+  name: default
+contexts:
+- context:
+    cluster: default
+    user: default
+  name: default
+current-context: default
+users:
+- name: default
+  user:
+    token: %s
+`
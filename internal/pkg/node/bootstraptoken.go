@@ -0,0 +1,45 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// newBootstrapToken generates a kubeadm-style bootstrap token
+// ("<6 chars>.<16 chars>") used to authenticate a worker node's
+// first contact with a cluster's kube-apiserver
+func newBootstrapToken() (string, error) {
+	tokenID, err := randomHexString(3)
+	if err != nil {
+		return "", err
+	}
+	tokenSecret, err := randomHexString(8)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s", tokenID, tokenSecret), nil
+}
+
+func randomHexString(numBytes int) (string, error) {
+	raw := make([]byte, numBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", raw), nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import "github.com/pkg/errors"
+
+// reconcileWaves groups componentTypes into waves: every component in
+// a wave only depends on components reconciled in a previous wave, so
+// all components within a wave can be reconciled in parallel
+func reconcileWaves(componentTypes []ComponentType, components map[ComponentType]Component) ([][]ComponentType, error) {
+	remaining := map[ComponentType]bool{}
+	for _, componentType := range componentTypes {
+		remaining[componentType] = true
+	}
+
+	var waves [][]ComponentType
+
+	for len(remaining) > 0 {
+		var wave []ComponentType
+		for componentType := range remaining {
+			ready := true
+			for _, dependency := range components[componentType].Dependencies() {
+				if remaining[dependency] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				wave = append(wave, componentType)
+			}
+		}
+		if len(wave) == 0 {
+			return nil, errors.New("component dependency graph has a cycle")
+		}
+		for _, componentType := range wave {
+			delete(remaining, componentType)
+		}
+		waves = append(waves, wave)
+	}
+
+	return waves, nil
+}
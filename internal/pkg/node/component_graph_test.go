@@ -0,0 +1,71 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+type fakeComponent struct {
+	dependencies []ComponentType
+}
+
+func (c *fakeComponent) Reconcile(*infra.Hypervisor) error       { return nil }
+func (c *fakeComponent) Rollback(*infra.Hypervisor) error        { return nil }
+func (c *fakeComponent) Healthy(*infra.Hypervisor) (bool, error) { return true, nil }
+func (c *fakeComponent) Dependencies() []ComponentType           { return c.dependencies }
+
+func TestReconcileWavesOrdersByDependency(t *testing.T) {
+	componentTypes := []ComponentType{
+		KubeAPIServerComponent,
+		KubeControllerManagerComponent,
+		KubeSchedulerComponent,
+	}
+	components := map[ComponentType]Component{
+		KubeAPIServerComponent:         &fakeComponent{},
+		KubeControllerManagerComponent: &fakeComponent{dependencies: []ComponentType{KubeAPIServerComponent}},
+		KubeSchedulerComponent:         &fakeComponent{dependencies: []ComponentType{KubeAPIServerComponent}},
+	}
+
+	waves, err := reconcileWaves(componentTypes, components)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(waves) != 2 {
+		t.Fatalf("expected 2 waves, got %d: %v", len(waves), waves)
+	}
+	if len(waves[0]) != 1 || waves[0][0] != KubeAPIServerComponent {
+		t.Fatalf("expected first wave to contain only KubeAPIServerComponent, got %v", waves[0])
+	}
+	if len(waves[1]) != 2 {
+		t.Fatalf("expected second wave to contain 2 components, got %v", waves[1])
+	}
+}
+
+func TestReconcileWavesDetectsCycle(t *testing.T) {
+	componentTypes := []ComponentType{KubeAPIServerComponent, KubeControllerManagerComponent}
+	components := map[ComponentType]Component{
+		KubeAPIServerComponent:         &fakeComponent{dependencies: []ComponentType{KubeControllerManagerComponent}},
+		KubeControllerManagerComponent: &fakeComponent{dependencies: []ComponentType{KubeAPIServerComponent}},
+	}
+
+	if _, err := reconcileWaves(componentTypes, components); err == nil {
+		t.Fatal("expected an error for a cyclic dependency graph, got nil")
+	}
+}
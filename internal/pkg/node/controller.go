@@ -0,0 +1,296 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	clusterv1alpha1 "oneinfra.ereslibre.es/m/apis/cluster/v1alpha1"
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+const controllerAgentName = "node-controller"
+
+// NodeLister gives the controller read access to Node, Cluster and
+// Hypervisor objects through their shared informer caches
+type NodeLister interface {
+	GetNode(clusterName, nodeName string) (*clusterv1alpha1.Node, error)
+	// ListNodes returns every Node belonging to clusterName, used to
+	// resolve the cluster's control plane hypervisor for worker bootstrap
+	ListNodes(clusterName string) ([]*clusterv1alpha1.Node, error)
+	GetHypervisor(hypervisorName string) (*infra.Hypervisor, error)
+	UpdateNodeStatus(node *clusterv1alpha1.Node) error
+}
+
+// NodeInformer is the subset of a shared informer this controller needs
+type NodeInformer interface {
+	AddEventHandler(handler cache.ResourceEventHandler)
+	HasSynced() bool
+}
+
+// NodeController reconciles Node objects using informer-driven,
+// workqueue-backed convergence instead of the one-shot Reconcile()
+// walk used by the CLI. Node.Specs()/Export() remain unaffected and
+// keep serving the CLI code paths
+type NodeController struct {
+	kubeclientset kubernetes.Interface
+	lister        NodeLister
+
+	workqueue workqueue.RateLimitingInterface
+	recorder  record.EventRecorder
+
+	nodeInformerSynced       cache.InformerSynced
+	clusterInformerSynced    cache.InformerSynced
+	hypervisorInformerSynced cache.InformerSynced
+}
+
+// NewNodeController returns a new NodeController, wiring event
+// handlers onto the Node, Cluster and Hypervisor informers. Node events
+// enqueue the node's key; Cluster and Hypervisor events enqueue the key
+// of the Cluster or Hypervisor object itself, not the keys of the
+// nodes it affects
+func NewNodeController(
+	kubeclientset kubernetes.Interface,
+	lister NodeLister,
+	nodeInformer NodeInformer,
+	clusterInformer NodeInformer,
+	hypervisorInformer NodeInformer,
+	eventBroadcaster record.EventBroadcaster,
+) *NodeController {
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: kubeclientset.CoreV1().Events(""),
+	})
+	recorder := eventBroadcaster.NewRecorder(clientgoscheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &NodeController{
+		kubeclientset:            kubeclientset,
+		lister:                   lister,
+		workqueue:                workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Nodes"),
+		recorder:                 recorder,
+		nodeInformerSynced:       nodeInformer.HasSynced,
+		clusterInformerSynced:    clusterInformer.HasSynced,
+		hypervisorInformerSynced: hypervisorInformer.HasSynced,
+	}
+
+	klog.Info("setting up event handlers")
+	nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueNode,
+		UpdateFunc: func(old, new interface{}) { controller.enqueueNode(new) },
+	})
+	clusterInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueNode,
+		UpdateFunc: func(old, new interface{}) { controller.enqueueNode(new) },
+	})
+	hypervisorInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueNode,
+		UpdateFunc: func(old, new interface{}) { controller.enqueueNode(new) },
+	})
+
+	return controller
+}
+
+// Run starts threadiness workers, blocking until stopCh is closed
+func (c *NodeController) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer runtime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	klog.Info("starting node controller")
+
+	klog.Info("waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.nodeInformerSynced, c.clusterInformerSynced, c.hypervisorInformerSynced); !ok {
+		return errors.New("failed waiting for caches to sync")
+	}
+
+	klog.Info("starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	klog.Info("started workers")
+	<-stopCh
+	klog.Info("shutting down workers")
+
+	return nil
+}
+
+func (c *NodeController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *NodeController) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.workqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.workqueue.Forget(obj)
+		runtime.HandleError(errors.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+
+	if err := c.syncHandler(key); err != nil {
+		c.workqueue.AddRateLimited(key)
+		runtime.HandleError(errors.Wrapf(err, "error syncing %q, requeuing", key))
+		return true
+	}
+
+	c.workqueue.Forget(obj)
+	klog.V(4).Infof("successfully synced %q", key)
+	return true
+}
+
+// syncHandler loads the Node identified by key, resolves its
+// hypervisor and reconciles each of its components, requeuing with
+// exponential backoff on failure. Once the node has been loaded, every
+// exit path, including failures, persists the node's conditions so
+// status.conditions always reflects the last observed outcome
+func (c *NodeController) syncHandler(key string) error {
+	clusterName, nodeName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(errors.Errorf("invalid resource key: %q", key))
+		return nil
+	}
+
+	cachedNode, err := c.lister.GetNode(clusterName, nodeName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("node %q no longer exists, dropping it from the queue", key)
+			return nil
+		}
+		return errors.Wrapf(err, "could not get node %q", key)
+	}
+	// nodeObject is mutated below to track conditions; never mutate the
+	// informer cache's object in place, so deep copy it first
+	nodeObject := cachedNode.DeepCopy()
+	c.setCondition(nodeObject, clusterv1alpha1.NodeReady, corev1.ConditionFalse, "Reconciling", "")
+
+	hypervisor, err := c.lister.GetHypervisor(nodeObject.Spec.Hypervisor)
+	if err != nil {
+		c.setCondition(nodeObject, clusterv1alpha1.NodeDegraded, corev1.ConditionTrue, "HypervisorUnavailable", err.Error())
+		c.recorder.Event(nodeObject, corev1.EventTypeWarning, "HypervisorUnavailable", err.Error())
+		c.persistStatus(nodeObject, key)
+		return errors.Wrapf(err, "could not get hypervisor %q for node %q", nodeObject.Spec.Hypervisor, key)
+	}
+
+	node, err := NewNodeWithHypervisorFromv1alpha1(nodeObject, hypervisor)
+	if err != nil {
+		c.setCondition(nodeObject, clusterv1alpha1.NodeDegraded, corev1.ConditionTrue, "NodeInvalid", err.Error())
+		c.recorder.Event(nodeObject, corev1.EventTypeWarning, "NodeInvalid", err.Error())
+		c.persistStatus(nodeObject, key)
+		return errors.Wrapf(err, "could not materialize node %q", key)
+	}
+
+	if nodeObject.Spec.Role == clusterv1alpha1.WorkerRole {
+		apiServerHypervisor, err := c.apiServerHypervisorFor(clusterName)
+		if err != nil {
+			c.setCondition(nodeObject, clusterv1alpha1.NodeDegraded, corev1.ConditionTrue, "APIServerHypervisorUnavailable", err.Error())
+			c.recorder.Event(nodeObject, corev1.EventTypeWarning, "APIServerHypervisorUnavailable", err.Error())
+			c.persistStatus(nodeObject, key)
+			return errors.Wrapf(err, "could not resolve kube-apiserver hypervisor for node %q", key)
+		}
+		node.SetAPIServerHypervisor(apiServerHypervisor)
+	}
+
+	c.setCondition(nodeObject, clusterv1alpha1.NodeProgressing, corev1.ConditionTrue, "Reconciling", "")
+	if err := node.Reconcile(); err != nil {
+		c.setCondition(nodeObject, clusterv1alpha1.NodeDegraded, corev1.ConditionTrue, "ReconcileFailed", err.Error())
+		c.recorder.Event(nodeObject, corev1.EventTypeWarning, "ReconcileFailed", err.Error())
+		c.persistStatus(nodeObject, key)
+		return errors.Wrapf(err, "could not reconcile node %q", key)
+	}
+
+	c.setCondition(nodeObject, clusterv1alpha1.NodeReady, corev1.ConditionTrue, "ReconcileSucceeded", "")
+	c.setCondition(nodeObject, clusterv1alpha1.NodeProgressing, corev1.ConditionFalse, "ReconcileSucceeded", "")
+	c.setCondition(nodeObject, clusterv1alpha1.NodeDegraded, corev1.ConditionFalse, "ReconcileSucceeded", "")
+	nodeObject.Status.ObservedGeneration = nodeObject.ObjectMeta.Generation
+	c.recorder.Event(nodeObject, corev1.EventTypeNormal, "Reconciled", fmt.Sprintf("node %q reconciled", nodeName))
+
+	return c.lister.UpdateNodeStatus(nodeObject)
+}
+
+// apiServerHypervisorFor returns the hypervisor hosting clusterName's
+// kube-apiserver, resolved from the cluster's existing control plane
+// nodes, mirroring oneinframachine.Reconciler.clusterAPIServerHypervisor
+func (c *NodeController) apiServerHypervisorFor(clusterName string) (*infra.Hypervisor, error) {
+	siblings, err := c.lister.ListNodes(clusterName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list nodes for cluster %q", clusterName)
+	}
+	for _, sibling := range siblings {
+		if sibling.Spec.Role == clusterv1alpha1.WorkerRole || sibling.Spec.Hypervisor == "" {
+			continue
+		}
+		if hypervisor, err := c.lister.GetHypervisor(sibling.Spec.Hypervisor); err == nil {
+			return hypervisor, nil
+		}
+	}
+	return nil, errors.Errorf("no control plane hypervisor found yet for cluster %q", clusterName)
+}
+
+// persistStatus saves nodeObject's status best-effort, only logging a
+// failure: it is called from failure paths that already have a more
+// specific error to return to the caller
+func (c *NodeController) persistStatus(nodeObject *clusterv1alpha1.Node, key string) {
+	if err := c.lister.UpdateNodeStatus(nodeObject); err != nil {
+		runtime.HandleError(errors.Wrapf(err, "could not update status for node %q", key))
+	}
+}
+
+func (c *NodeController) setCondition(node *clusterv1alpha1.Node, conditionType clusterv1alpha1.NodeConditionType, status corev1.ConditionStatus, reason, message string) {
+	condition := clusterv1alpha1.NodeCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	for i, existing := range node.Status.Conditions {
+		if existing.Type == conditionType {
+			node.Status.Conditions[i] = condition
+			return
+		}
+	}
+	node.Status.Conditions = append(node.Status.Conditions, condition)
+}
+
+func (c *NodeController) enqueueNode(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
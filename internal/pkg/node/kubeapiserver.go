@@ -0,0 +1,67 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/pkg/errors"
+
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+// KubeAPIServer represents a kube-apiserver component running on a
+// control plane node
+type KubeAPIServer struct {
+	ClusterName string
+}
+
+// Reconcile reconciles the kube-apiserver component
+func (kubeAPIServer *KubeAPIServer) Reconcile(hypervisor *infra.Hypervisor) error {
+	return hypervisor.UploadFile(kubeAPIServerManifestPath, kubeAPIServerManifest)
+}
+
+// Dependencies returns the components that must be reconciled on this
+// node before the kube-apiserver. It has none: it is the first
+// control plane component to come up
+func (kubeAPIServer *KubeAPIServer) Dependencies() []ComponentType {
+	return nil
+}
+
+// Rollback undoes a successful Reconcile, removing the static pod manifest
+func (kubeAPIServer *KubeAPIServer) Rollback(hypervisor *infra.Hypervisor) error {
+	if err := hypervisor.DeleteFile(kubeAPIServerManifestPath); err != nil {
+		return errors.Wrap(err, "could not roll back kube-apiserver")
+	}
+	return nil
+}
+
+// Healthy reports whether the kube-apiserver is up and serving
+func (kubeAPIServer *KubeAPIServer) Healthy(hypervisor *infra.Hypervisor) (bool, error) {
+	return hypervisor.ComponentHealthy(kubeAPIServer.ClusterName, "kube-apiserver")
+}
+
+const kubeAPIServerManifestPath = "/etc/kubernetes/manifests/kube-apiserver.yaml"
+
+const kubeAPIServerManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-apiserver
+  namespace: kube-system
+spec:
+  containers:
+    - name: kube-apiserver
+      image: k8s.gcr.io/kube-apiserver:latest
+`
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/pkg/errors"
+
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+// KubeControllerManager represents a kube-controller-manager component
+// running on a control plane node
+type KubeControllerManager struct {
+	ClusterName string
+}
+
+// Reconcile reconciles the kube-controller-manager component
+func (kubeControllerManager *KubeControllerManager) Reconcile(hypervisor *infra.Hypervisor) error {
+	return hypervisor.UploadFile(kubeControllerManagerManifestPath, kubeControllerManagerManifest)
+}
+
+// Dependencies returns the components that must be reconciled on this
+// node before the kube-controller-manager: it talks to the
+// kube-apiserver on this same node, so it must come up after it
+func (kubeControllerManager *KubeControllerManager) Dependencies() []ComponentType {
+	return []ComponentType{KubeAPIServerComponent}
+}
+
+// Rollback undoes a successful Reconcile, removing the static pod manifest
+func (kubeControllerManager *KubeControllerManager) Rollback(hypervisor *infra.Hypervisor) error {
+	if err := hypervisor.DeleteFile(kubeControllerManagerManifestPath); err != nil {
+		return errors.Wrap(err, "could not roll back kube-controller-manager")
+	}
+	return nil
+}
+
+// Healthy reports whether the kube-controller-manager is up
+func (kubeControllerManager *KubeControllerManager) Healthy(hypervisor *infra.Hypervisor) (bool, error) {
+	return hypervisor.ComponentHealthy(kubeControllerManager.ClusterName, "kube-controller-manager")
+}
+
+const kubeControllerManagerManifestPath = "/etc/kubernetes/manifests/kube-controller-manager.yaml"
+
+const kubeControllerManagerManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-controller-manager
+  namespace: kube-system
+spec:
+  containers:
+    - name: kube-controller-manager
+      image: k8s.gcr.io/kube-controller-manager:latest
+`
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/pkg/errors"
+
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+// Kubelet represents a kubelet component running on a worker node
+type Kubelet struct {
+	ClusterName string
+	// APIServerHypervisor is the hypervisor hosting this cluster's
+	// kube-apiserver, resolved from the cluster's control plane nodes
+	// by the code that builds the Node. It is never the worker's own hypervisor:
+	// workers and control plane nodes are scheduled onto disjoint
+	// public/private hypervisor pools
+	APIServerHypervisor *infra.Hypervisor
+}
+
+// Reconcile reconciles the kubelet component
+func (kubelet *Kubelet) Reconcile(hypervisor *infra.Hypervisor) error {
+	bootstrapKubeConfig, err := bootstrapKubeConfigFor(kubelet.ClusterName, kubelet.APIServerHypervisor)
+	if err != nil {
+		return errors.Wrap(err, "could not generate kubelet bootstrap kubeconfig")
+	}
+	return hypervisor.UploadFile(kubeletBootstrapKubeConfigPath(kubelet.ClusterName), bootstrapKubeConfig)
+}
+
+// Dependencies returns the components that must be reconciled on this
+// node before the kubelet. The kubelet's hard dependency is the
+// cluster's kube-apiserver, which lives on another node and is
+// therefore not part of this node's reconcile graph; bootstrapKubeConfigFor
+// resolves its endpoint directly and returns an error, without
+// waiting, when it cannot be resolved
+func (kubelet *Kubelet) Dependencies() []ComponentType {
+	return nil
+}
+
+// Rollback undoes a successful Reconcile, removing the bootstrap
+// kubeconfig so a retried reconcile starts from a clean state
+func (kubelet *Kubelet) Rollback(hypervisor *infra.Hypervisor) error {
+	return hypervisor.DeleteFile(kubeletBootstrapKubeConfigPath(kubelet.ClusterName))
+}
+
+// Healthy reports whether the kubelet is up and has joined the cluster
+func (kubelet *Kubelet) Healthy(hypervisor *infra.Hypervisor) (bool, error) {
+	return hypervisor.ComponentHealthy(kubelet.ClusterName, "kubelet")
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/pkg/errors"
+
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+// KubeProxy represents a kube-proxy component running on a worker node
+type KubeProxy struct {
+	ClusterName string
+	// APIServerHypervisor is the hypervisor hosting this cluster's
+	// kube-apiserver, resolved from the cluster's control plane nodes
+	// by the code that builds the Node. It is never the worker's own hypervisor:
+	// workers and control plane nodes are scheduled onto disjoint
+	// public/private hypervisor pools
+	APIServerHypervisor *infra.Hypervisor
+}
+
+// Reconcile reconciles the kube-proxy component
+func (kubeProxy *KubeProxy) Reconcile(hypervisor *infra.Hypervisor) error {
+	kubeConfig, err := bootstrapKubeConfigFor(kubeProxy.ClusterName, kubeProxy.APIServerHypervisor)
+	if err != nil {
+		return errors.Wrap(err, "could not generate kube-proxy kubeconfig")
+	}
+	return hypervisor.UploadFile(kubeProxyKubeConfigPath(kubeProxy.ClusterName), kubeConfig)
+}
+
+// Dependencies returns the components that must be reconciled on this
+// node before kube-proxy. kube-proxy relies on the kubelet having
+// already registered the node, so it waits for it
+func (kubeProxy *KubeProxy) Dependencies() []ComponentType {
+	return []ComponentType{KubeletComponent}
+}
+
+// Rollback undoes a successful Reconcile, removing the generated kubeconfig
+func (kubeProxy *KubeProxy) Rollback(hypervisor *infra.Hypervisor) error {
+	return hypervisor.DeleteFile(kubeProxyKubeConfigPath(kubeProxy.ClusterName))
+}
+
+// Healthy reports whether kube-proxy is up and programming the data plane
+func (kubeProxy *KubeProxy) Healthy(hypervisor *infra.Hypervisor) (bool, error) {
+	return hypervisor.ComponentHealthy(kubeProxy.ClusterName, "kube-proxy")
+}
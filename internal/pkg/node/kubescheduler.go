@@ -0,0 +1,67 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/pkg/errors"
+
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+// KubeScheduler represents a kube-scheduler component running on a
+// control plane node
+type KubeScheduler struct {
+	ClusterName string
+}
+
+// Reconcile reconciles the kube-scheduler component
+func (kubeScheduler *KubeScheduler) Reconcile(hypervisor *infra.Hypervisor) error {
+	return hypervisor.UploadFile(kubeSchedulerManifestPath, kubeSchedulerManifest)
+}
+
+// Dependencies returns the components that must be reconciled on this
+// node before the kube-scheduler: it talks to the kube-apiserver on
+// this same node, so it must come up after it
+func (kubeScheduler *KubeScheduler) Dependencies() []ComponentType {
+	return []ComponentType{KubeAPIServerComponent}
+}
+
+// Rollback undoes a successful Reconcile, removing the static pod manifest
+func (kubeScheduler *KubeScheduler) Rollback(hypervisor *infra.Hypervisor) error {
+	if err := hypervisor.DeleteFile(kubeSchedulerManifestPath); err != nil {
+		return errors.Wrap(err, "could not roll back kube-scheduler")
+	}
+	return nil
+}
+
+// Healthy reports whether the kube-scheduler is up
+func (kubeScheduler *KubeScheduler) Healthy(hypervisor *infra.Hypervisor) (bool, error) {
+	return hypervisor.ComponentHealthy(kubeScheduler.ClusterName, "kube-scheduler")
+}
+
+const kubeSchedulerManifestPath = "/etc/kubernetes/manifests/kube-scheduler.yaml"
+
+const kubeSchedulerManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: kube-scheduler
+  namespace: kube-system
+spec:
+  containers:
+    - name: kube-scheduler
+      image: k8s.gcr.io/kube-scheduler:latest
+`
@@ -17,9 +17,13 @@ limitations under the License.
 package node
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -30,33 +34,96 @@ import (
 )
 
 var (
-	components = []ComponentType{
+	controlPlaneComponents = []ComponentType{
 		KubeAPIServerComponent,
 		KubeControllerManagerComponent,
 		KubeSchedulerComponent,
 	}
+	workerComponents = []ComponentType{
+		KubeletComponent,
+		KubeProxyComponent,
+	}
 )
 
-// Node represents a Control Plane node
+// componentsForRole returns the ordered list of components that make
+// up a node with the given role
+func componentsForRole(role clusterv1alpha1.Role) []ComponentType {
+	if role == clusterv1alpha1.WorkerRole {
+		return workerComponents
+	}
+	return controlPlaneComponents
+}
+
+// Node represents a node, either a control plane node or a worker node
 type Node struct {
 	Name           string
 	HypervisorName string
 	ClusterName    string
+	Role           clusterv1alpha1.Role
 	hypervisor     *infra.Hypervisor
+	// apiServerHypervisor is the hypervisor hosting this node's
+	// cluster's KubeAPIServerComponent, used by worker components
+	// (kubelet, kube-proxy) to bootstrap against the right host
+	// instead of the worker's own hypervisor
+	apiServerHypervisor *infra.Hypervisor
+}
+
+// SetAPIServerHypervisor records which hypervisor hosts this node's
+// cluster's kube-apiserver, so worker components know where to
+// bootstrap against
+func (node *Node) SetAPIServerHypervisor(hypervisor *infra.Hypervisor) {
+	node.apiServerHypervisor = hypervisor
+}
+
+// controlPlaneHypervisorOf returns the hypervisor of a control plane
+// node of clusterName out of existing, or nil if none is scheduled yet
+func controlPlaneHypervisorOf(clusterName string, existing List) *infra.Hypervisor {
+	for _, node := range existing {
+		if node.ClusterName == clusterName && node.Role != clusterv1alpha1.WorkerRole && node.hypervisor != nil {
+			return node.hypervisor
+		}
+	}
+	return nil
 }
 
 // List represents a list of nodes
 type List []*Node
 
-// NewNodeWithRandomHypervisor creates a node with a random hypervisor from the provided hypervisorList
-func NewNodeWithRandomHypervisor(nodeName, clusterName string, hypervisorList infra.HypervisorList) *Node {
-	hypervisorSample := hypervisorList.Sample()
-	return &Node{
+// NewNodeWithRandomHypervisor creates a node, picking its hypervisor
+// out of hypervisorList (restricted to the hypervisors matching the
+// given role: "public" hypervisors for worker nodes, "private"
+// hypervisors for control plane nodes) using the cluster's configured
+// scheduler policy
+func NewNodeWithRandomHypervisor(nodeName, clusterName string, role clusterv1alpha1.Role, policy clusterv1alpha1.SchedulerPolicy, hypervisorList infra.HypervisorList) *Node {
+	return NewNodeWithScheduler(nodeName, clusterName, role, SchedulerFor(policy), hypervisorList, nil)
+}
+
+// NewNodeWithScheduler creates a node, picking its hypervisor out of
+// the hypervisorList matching role ("public" hypervisors for worker
+// nodes, "private" hypervisors for control plane nodes) using the
+// given scheduler. existing holds the nodes already scheduled, so
+// schedulers can take cluster-wide placement into account
+func NewNodeWithScheduler(nodeName, clusterName string, role clusterv1alpha1.Role, scheduler Scheduler, hypervisorList infra.HypervisorList, existing List) *Node {
+	hypervisorPool := hypervisorList.Private()
+	if role == clusterv1alpha1.WorkerRole {
+		hypervisorPool = hypervisorList.Public()
+	}
+	node := &Node{
 		Name:           nodeName,
-		HypervisorName: hypervisorSample.Name,
+		HypervisorName: "",
 		ClusterName:    clusterName,
-		hypervisor:     hypervisorSample,
+		Role:           role,
+	}
+	hypervisorScore, err := scheduler.Score(node, hypervisorPool, existing)
+	if err != nil {
+		return node
 	}
+	node.HypervisorName = hypervisorScore.Name
+	node.hypervisor = hypervisorScore
+	if role == clusterv1alpha1.WorkerRole {
+		node.apiServerHypervisor = controlPlaneHypervisorOf(clusterName, existing)
+	}
+	return node
 }
 
 // NewNodeFromv1alpha1 returns a node based on a versioned node
@@ -65,6 +132,7 @@ func NewNodeFromv1alpha1(node *clusterv1alpha1.Node) (*Node, error) {
 		Name:           node.ObjectMeta.Name,
 		HypervisorName: node.Spec.Hypervisor,
 		ClusterName:    node.Spec.Cluster,
+		Role:           node.Spec.Role,
 	}, nil
 }
 
@@ -74,6 +142,7 @@ func NewNodeWithHypervisorFromv1alpha1(node *clusterv1alpha1.Node, hypervisor *i
 		Name:           node.ObjectMeta.Name,
 		HypervisorName: node.Spec.Hypervisor,
 		ClusterName:    node.Spec.Cluster,
+		Role:           node.Spec.Role,
 		hypervisor:     hypervisor,
 	}, nil
 }
@@ -82,35 +151,141 @@ func NewNodeWithHypervisorFromv1alpha1(node *clusterv1alpha1.Node, hypervisor *i
 func (node *Node) Component(componentType ComponentType) (Component, error) {
 	switch componentType {
 	case KubeAPIServerComponent:
-		return &KubeAPIServer{}, nil
+		return &KubeAPIServer{ClusterName: node.ClusterName}, nil
 	case KubeControllerManagerComponent:
-		return &KubeControllerManager{}, nil
+		return &KubeControllerManager{ClusterName: node.ClusterName}, nil
 	case KubeSchedulerComponent:
-		return &KubeScheduler{}, nil
+		return &KubeScheduler{ClusterName: node.ClusterName}, nil
+	case KubeletComponent:
+		return &Kubelet{ClusterName: node.ClusterName, APIServerHypervisor: node.apiServerHypervisor}, nil
+	case KubeProxyComponent:
+		return &KubeProxy{ClusterName: node.ClusterName, APIServerHypervisor: node.apiServerHypervisor}, nil
 	default:
 		return nil, errors.Errorf("unknown component: %d", componentType)
 	}
 }
 
-// Reconcile reconciles the node
+// Reconcile reconciles the node. Components that don't depend on one
+// another (per their Dependencies()) are reconciled concurrently; if
+// any component fails, its siblings are cancelled and every component
+// that had already succeeded in this pass is rolled back, in reverse
+// dependency order, so the hypervisor is left in a known-good state
 func (node *Node) Reconcile() error {
 	if node.hypervisor == nil {
 		return errors.Errorf("node %q is missing an hypervisor", node.Name)
 	}
-	for _, componentType := range components {
+
+	componentTypes := componentsForRole(node.Role)
+	components := map[ComponentType]Component{}
+	for _, componentType := range componentTypes {
 		component, err := node.Component(componentType)
 		if err != nil {
 			return err
 		}
-		if err := component.Reconcile(node.hypervisor); err != nil {
+		components[componentType] = component
+	}
+
+	waves, err := reconcileWaves(componentTypes, components)
+	if err != nil {
+		return err
+	}
+
+	var succeeded []ComponentType
+	for _, wave := range waves {
+		g, ctx := errgroup.WithContext(context.Background())
+		var mu sync.Mutex
+		for _, componentType := range wave {
+			componentType := componentType
+			component := components[componentType]
+			g.Go(func() error {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if err := component.Reconcile(node.hypervisor); err != nil {
+					return errors.Wrapf(err, "could not reconcile component %d of node %q", componentType, node.Name)
+				}
+				mu.Lock()
+				succeeded = append(succeeded, componentType)
+				mu.Unlock()
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			if rollbackErr := node.rollback(components, succeeded); rollbackErr != nil {
+				return errors.Wrapf(err, "node %q left in an inconsistent state, rollback also failed: %s", node.Name, rollbackErr)
+			}
+			return err
+		}
+	}
+
+	return node.checkHealth(components, componentTypes)
+}
+
+// Delete tears down every component this node's role materializes,
+// using each component's own Rollback so the exact paths Reconcile
+// wrote are the ones removed
+func (node *Node) Delete() error {
+	if node.hypervisor == nil {
+		return errors.Errorf("node %q is missing an hypervisor", node.Name)
+	}
+
+	componentTypes := componentsForRole(node.Role)
+	components := map[ComponentType]Component{}
+	for _, componentType := range componentTypes {
+		component, err := node.Component(componentType)
+		if err != nil {
 			return err
 		}
+		components[componentType] = component
+	}
+
+	if err := node.rollback(components, componentTypes); err != nil {
+		return errors.Wrapf(err, "could not delete node %q", node.Name)
+	}
+	return nil
+}
+
+// rollback runs Rollback on every component in succeeded, in reverse
+// order, best-effort: every component is given a chance to roll back
+// even if an earlier one failed, and every failure is aggregated into
+// the returned error so the caller learns the hypervisor may still
+// carry partially configured components
+func (node *Node) rollback(components map[ComponentType]Component, succeeded []ComponentType) error {
+	var rollbackErrors []string
+	for i := len(succeeded) - 1; i >= 0; i-- {
+		componentType := succeeded[i]
+		if err := components[componentType].Rollback(node.hypervisor); err != nil {
+			rollbackErrors = append(rollbackErrors, errors.Wrapf(err, "component %d", componentType).Error())
+		}
+	}
+	if len(rollbackErrors) > 0 {
+		return errors.Errorf("%s", strings.Join(rollbackErrors, "; "))
+	}
+	return nil
+}
+
+// checkHealth runs each component's health probe and returns an
+// aggregate error naming every component that did not come up healthy
+func (node *Node) checkHealth(components map[ComponentType]Component, componentTypes []ComponentType) error {
+	var unhealthy []ComponentType
+	for _, componentType := range componentTypes {
+		healthy, err := components[componentType].Healthy(node.hypervisor)
+		if err != nil || !healthy {
+			unhealthy = append(unhealthy, componentType)
+		}
+	}
+	if len(unhealthy) > 0 {
+		return errors.Errorf("node %q has unhealthy components: %v", node.Name, unhealthy)
 	}
 	return nil
 }
 
 // Export exports the node to a versioned node
 func (node *Node) Export() *clusterv1alpha1.Node {
+	role := node.Role
+	if role == "" {
+		role = clusterv1alpha1.ControlPlaneRole
+	}
 	return &clusterv1alpha1.Node{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: node.Name,
@@ -118,7 +293,7 @@ func (node *Node) Export() *clusterv1alpha1.Node {
 		Spec: clusterv1alpha1.NodeSpec{
 			Hypervisor: node.HypervisorName,
 			Cluster:    node.ClusterName,
-			Role:       clusterv1alpha1.ControlPlaneRole,
+			Role:       role,
 		},
 	}
 }
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/pkg/errors"
+
+	clusterv1alpha1 "oneinfra.ereslibre.es/m/apis/cluster/v1alpha1"
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+// Scheduler picks the hypervisor a node should be placed on out of a
+// set of candidates, optionally taking the nodes already scheduled
+// into account
+type Scheduler interface {
+	// Score returns the candidate hypervisor chosen for node
+	Score(node *Node, candidates infra.HypervisorList, existing List) (*infra.Hypervisor, error)
+}
+
+// SchedulerFor returns the scheduler implementation for the given
+// cluster scheduler policy, defaulting to RandomScheduler when policy
+// is empty or unknown
+func SchedulerFor(policy clusterv1alpha1.SchedulerPolicy) Scheduler {
+	base := schedulerForPolicy(policy)
+	return &antiAffinityScheduler{scheduler: base}
+}
+
+func schedulerForPolicy(policy clusterv1alpha1.SchedulerPolicy) Scheduler {
+	switch policy {
+	case clusterv1alpha1.BinPackSchedulerPolicy:
+		return &BinPackScheduler{}
+	case clusterv1alpha1.SpreadSchedulerPolicy:
+		return &SpreadScheduler{}
+	default:
+		return &RandomScheduler{}
+	}
+}
+
+// RandomScheduler schedules a node on a random candidate hypervisor
+type RandomScheduler struct{}
+
+// Score returns a random candidate hypervisor
+func (scheduler *RandomScheduler) Score(node *Node, candidates infra.HypervisorList, existing List) (*infra.Hypervisor, error) {
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("no candidate hypervisors to schedule node %q", node.Name)
+	}
+	return candidates.Sample(), nil
+}
+
+// BinPackScheduler schedules a node on the candidate hypervisor that
+// already hosts the most components, minimizing fragmentation across
+// the hypervisor pool
+type BinPackScheduler struct{}
+
+// Score returns the most packed candidate hypervisor
+func (scheduler *BinPackScheduler) Score(node *Node, candidates infra.HypervisorList, existing List) (*infra.Hypervisor, error) {
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("no candidate hypervisors to schedule node %q", node.Name)
+	}
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.ComponentCount() > best.ComponentCount() {
+			best = candidate
+		}
+	}
+	return best, nil
+}
+
+// SpreadScheduler schedules a node on the candidate hypervisor that
+// hosts the fewest nodes of the same cluster, spreading a cluster's
+// nodes across the hypervisor pool
+type SpreadScheduler struct{}
+
+// Score returns the least loaded candidate hypervisor for node's cluster
+func (scheduler *SpreadScheduler) Score(node *Node, candidates infra.HypervisorList, existing List) (*infra.Hypervisor, error) {
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("no candidate hypervisors to schedule node %q", node.Name)
+	}
+	best := candidates[0]
+	bestCount := existing.countOnHypervisor(node.ClusterName, best.Name)
+	for _, candidate := range candidates[1:] {
+		if count := existing.countOnHypervisor(node.ClusterName, candidate.Name); count < bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best, nil
+}
+
+// antiAffinityScheduler wraps a Scheduler, refusing to colocate two
+// control plane nodes of the same cluster on a single hypervisor
+// unless no other candidate is available
+type antiAffinityScheduler struct {
+	scheduler Scheduler
+}
+
+func (scheduler *antiAffinityScheduler) Score(node *Node, candidates infra.HypervisorList, existing List) (*infra.Hypervisor, error) {
+	if node.Role != clusterv1alpha1.WorkerRole {
+		if relaxed := existing.withoutControlPlaneOf(node.ClusterName, candidates); len(relaxed) > 0 {
+			candidates = relaxed
+		}
+	}
+	return scheduler.scheduler.Score(node, candidates, existing)
+}
+
+// countOnHypervisor returns how many nodes of clusterName are already
+// scheduled on the hypervisor named hypervisorName
+func (list List) countOnHypervisor(clusterName, hypervisorName string) int {
+	count := 0
+	for _, node := range list {
+		if node.ClusterName == clusterName && node.HypervisorName == hypervisorName {
+			count++
+		}
+	}
+	return count
+}
+
+// withoutControlPlaneOf filters out candidates that already host a
+// control plane node of clusterName
+func (list List) withoutControlPlaneOf(clusterName string, candidates infra.HypervisorList) infra.HypervisorList {
+	occupied := map[string]bool{}
+	for _, node := range list {
+		if node.ClusterName == clusterName && node.Role != clusterv1alpha1.WorkerRole {
+			occupied[node.HypervisorName] = true
+		}
+	}
+	var res infra.HypervisorList
+	for _, candidate := range candidates {
+		if !occupied[candidate.Name] {
+			res = append(res, candidate)
+		}
+	}
+	return res
+}
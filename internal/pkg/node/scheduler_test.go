@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Rafael Fernández López <ereslibre@ereslibre.es>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	clusterv1alpha1 "oneinfra.ereslibre.es/m/apis/cluster/v1alpha1"
+	"oneinfra.ereslibre.es/m/internal/pkg/infra"
+)
+
+func TestRandomSchedulerRequiresCandidates(t *testing.T) {
+	scheduler := &RandomScheduler{}
+	if _, err := scheduler.Score(&Node{Name: "n1"}, nil, nil); err == nil {
+		t.Fatal("expected an error when no candidate hypervisors are available")
+	}
+}
+
+func TestBinPackSchedulerRequiresCandidates(t *testing.T) {
+	scheduler := &BinPackScheduler{}
+	if _, err := scheduler.Score(&Node{Name: "n1"}, nil, nil); err == nil {
+		t.Fatal("expected an error when no candidate hypervisors are available")
+	}
+}
+
+func TestSpreadSchedulerPrefersLeastLoadedHypervisor(t *testing.T) {
+	h1 := &infra.Hypervisor{Name: "h1"}
+	h2 := &infra.Hypervisor{Name: "h2"}
+	candidates := infra.HypervisorList{h1, h2}
+	existing := List{
+		{Name: "n1", ClusterName: "cluster", HypervisorName: "h1", Role: clusterv1alpha1.ControlPlaneRole},
+	}
+
+	scheduler := &SpreadScheduler{}
+	picked, err := scheduler.Score(&Node{Name: "n2", ClusterName: "cluster"}, candidates, existing)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if picked.Name != "h2" {
+		t.Fatalf("expected the least loaded hypervisor %q to be picked, got %q", "h2", picked.Name)
+	}
+}
+
+func TestAntiAffinitySchedulerAvoidsColocatingControlPlaneNodes(t *testing.T) {
+	h1 := &infra.Hypervisor{Name: "h1"}
+	h2 := &infra.Hypervisor{Name: "h2"}
+	candidates := infra.HypervisorList{h1, h2}
+	existing := List{
+		{Name: "n1", ClusterName: "cluster", HypervisorName: "h1", Role: clusterv1alpha1.ControlPlaneRole},
+	}
+
+	scheduler := SchedulerFor(clusterv1alpha1.RandomSchedulerPolicy)
+	picked, err := scheduler.Score(&Node{Name: "n2", ClusterName: "cluster", Role: clusterv1alpha1.ControlPlaneRole}, candidates, existing)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if picked.Name != "h2" {
+		t.Fatalf("expected the anti-affinity scheduler to avoid h1, got %q", picked.Name)
+	}
+}
+
+func TestAntiAffinitySchedulerRelaxesWhenNoOtherCandidate(t *testing.T) {
+	h1 := &infra.Hypervisor{Name: "h1"}
+	candidates := infra.HypervisorList{h1}
+	existing := List{
+		{Name: "n1", ClusterName: "cluster", HypervisorName: "h1", Role: clusterv1alpha1.ControlPlaneRole},
+	}
+
+	scheduler := SchedulerFor(clusterv1alpha1.RandomSchedulerPolicy)
+	picked, err := scheduler.Score(&Node{Name: "n2", ClusterName: "cluster", Role: clusterv1alpha1.ControlPlaneRole}, candidates, existing)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if picked.Name != "h1" {
+		t.Fatalf("expected the anti-affinity scheduler to fall back to the only candidate, got %q", picked.Name)
+	}
+}